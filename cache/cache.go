@@ -0,0 +1,71 @@
+// Package cache provides a small in-memory, TTL-based cache used to avoid
+// re-fetching the same secret on every invocation (e.g. from the "exec"
+// subcommand, which may be re-run frequently by a process supervisor).
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Key identifies a cached value by secret ID and version stage.
+type Key struct {
+	SecretID string
+	Stage    string
+}
+
+// FetchFunc retrieves the underlying value for key, called on a cache miss
+// or expiry.
+type FetchFunc func(ctx context.Context, key Key) (string, error)
+
+// Cache is an in-memory TTL cache of string values keyed by Key. It is safe
+// for concurrent use.
+type Cache struct {
+	ttl   time.Duration
+	fetch FetchFunc
+
+	mu      sync.Mutex
+	entries map[Key]entry
+}
+
+type entry struct {
+	value     string
+	fetchedAt time.Time
+}
+
+// New creates a Cache that calls fetch on a miss or expiry and caches
+// results for ttl. A ttl <= 0 disables caching: every Get calls fetch.
+func New(ttl time.Duration, fetch FetchFunc) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		fetch:   fetch,
+		entries: make(map[Key]entry),
+	}
+}
+
+// Get returns the cached value for key if it is still within ttl. On a miss
+// or expiry it calls fetch, caches the result, and returns it.
+func (c *Cache) Get(ctx context.Context, key Key) (string, error) {
+	if c.ttl > 0 {
+		c.mu.Lock()
+		e, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok && time.Since(e.fetchedAt) < c.ttl {
+			return e.value, nil
+		}
+	}
+
+	value, err := c.fetch(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	if c.ttl > 0 {
+		c.mu.Lock()
+		c.entries[key] = entry{value: value, fetchedAt: time.Now()}
+		c.mu.Unlock()
+	}
+
+	return value, nil
+}
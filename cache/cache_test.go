@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCache_CachesWithinTTL(t *testing.T) {
+	calls := 0
+	c := New(time.Minute, func(ctx context.Context, key Key) (string, error) {
+		calls++
+		return "value-for-" + key.SecretID, nil
+	})
+
+	key := Key{SecretID: "my-secret", Stage: "AWSCURRENT"}
+
+	got, err := c.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if got != "value-for-my-secret" {
+		t.Fatalf("Get() = %q, want %q", got, "value-for-my-secret")
+	}
+
+	if _, err := c.Get(context.Background(), key); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("fetch called %d times, want 1 (second Get should hit the cache)", calls)
+	}
+}
+
+func TestCache_RefetchesAfterTTL(t *testing.T) {
+	calls := 0
+	c := New(time.Millisecond, func(ctx context.Context, key Key) (string, error) {
+		calls++
+		return "value", nil
+	})
+
+	key := Key{SecretID: "my-secret", Stage: "AWSCURRENT"}
+	if _, err := c.Get(context.Background(), key); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := c.Get(context.Background(), key); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("fetch called %d times, want 2 (entry should have expired)", calls)
+	}
+}
+
+func TestCache_ZeroTTLDisablesCaching(t *testing.T) {
+	calls := 0
+	c := New(0, func(ctx context.Context, key Key) (string, error) {
+		calls++
+		return "value", nil
+	})
+
+	key := Key{SecretID: "my-secret", Stage: "AWSCURRENT"}
+	if _, err := c.Get(context.Background(), key); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	if _, err := c.Get(context.Background(), key); err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("fetch called %d times, want 2 (ttl=0 should disable caching)", calls)
+	}
+}
+
+func TestCache_DistinctKeysDoNotShareEntries(t *testing.T) {
+	c := New(time.Minute, func(ctx context.Context, key Key) (string, error) {
+		return key.Stage, nil
+	})
+
+	current, err := c.Get(context.Background(), Key{SecretID: "my-secret", Stage: "AWSCURRENT"})
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+	previous, err := c.Get(context.Background(), Key{SecretID: "my-secret", Stage: "AWSPREVIOUS"})
+	if err != nil {
+		t.Fatalf("Get() unexpected error: %v", err)
+	}
+
+	if current != "AWSCURRENT" || previous != "AWSPREVIOUS" {
+		t.Fatalf("Get() = (%q, %q), want (AWSCURRENT, AWSPREVIOUS)", current, previous)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"github.com/hypolas/aws-ssm-light/cache"
+)
+
+// cachingSecretsManagerClient wraps a SecretsManagerClient with an
+// in-memory TTL cache (see the cache package), keyed by SecretId and
+// VersionStage. It lets --cache-ttl avoid re-fetching the same secret on
+// every invocation, which matters most for the "exec" subcommand when it's
+// re-run frequently by a process supervisor.
+type cachingSecretsManagerClient struct {
+	client SecretsManagerClient
+	cache  *cache.Cache
+}
+
+// newCachingSecretsManagerClient wraps client with a cache.Cache holding
+// entries for ttl. A ttl <= 0 effectively disables caching.
+func newCachingSecretsManagerClient(client SecretsManagerClient, ttl time.Duration) *cachingSecretsManagerClient {
+	c := &cachingSecretsManagerClient{client: client}
+	c.cache = cache.New(ttl, func(ctx context.Context, key cache.Key) (string, error) {
+		input := &secretsmanager.GetSecretValueInput{SecretId: &key.SecretID}
+		if key.Stage != "" {
+			input.VersionStage = &key.Stage
+		}
+		result, err := client.GetSecretValue(ctx, input)
+		if err != nil {
+			return "", err
+		}
+		if result.SecretString == nil {
+			return "", fmt.Errorf("secret does not contain a string value")
+		}
+		return *result.SecretString, nil
+	})
+	return c
+}
+
+// GetSecretValue implements SecretsManagerClient. Lookups pinned to a
+// specific VersionId bypass the cache: a pinned version is immutable, but
+// caching it under a VersionStage-only key could hand the wrong version to
+// a sibling call that pins a different VersionId.
+func (c *cachingSecretsManagerClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	if params.VersionId != nil {
+		return c.client.GetSecretValue(ctx, params, optFns...)
+	}
+
+	stage := ""
+	if params.VersionStage != nil {
+		stage = *params.VersionStage
+	}
+
+	value, err := c.cache.Get(ctx, cache.Key{SecretID: strPtrValue(params.SecretId), Stage: stage})
+	if err != nil {
+		return nil, err
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: &value}, nil
+}
+
+func strPtrValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
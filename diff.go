@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp is one line of an edit script produced by diffLines: kept (' '),
+// removed ('-') from a, or added ('+') from b.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines computes a minimal line-level edit script turning a into b,
+// using the standard LCS (longest common subsequence) table. It's O(n*m),
+// which is fine for secret-sized JSON payloads.
+func diffLines(a, b []string) []diffOp {
+	n, m := len(a), len(b)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// UnifiedDiff returns a line-level diff between a and b in the style of
+// `diff -u`, labeled aLabel/bLabel in the "---"/"+++" header. It returns ""
+// when a and b are identical.
+func UnifiedDiff(aLabel, bLabel, a, b string) string {
+	if a == b {
+		return ""
+	}
+
+	ops := diffLines(strings.Split(a, "\n"), strings.Split(b, "\n"))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n", aLabel)
+	fmt.Fprintf(&sb, "+++ %s\n", bLabel)
+	for _, op := range ops {
+		switch op.kind {
+		case ' ':
+			fmt.Fprintf(&sb, "  %s\n", op.line)
+		case '-':
+			fmt.Fprintf(&sb, "- %s\n", op.line)
+		case '+':
+			fmt.Fprintf(&sb, "+ %s\n", op.line)
+		}
+	}
+	return sb.String()
+}
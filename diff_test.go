@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want string
+	}{
+		{
+			name: "identical",
+			a:    "line1\nline2",
+			b:    "line1\nline2",
+			want: "",
+		},
+		{
+			name: "single line changed",
+			a:    "line1\nline2\nline3",
+			b:    "line1\nchanged\nline3",
+			want: "--- old\n+++ new\n  line1\n- line2\n+ changed\n  line3\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnifiedDiff("old", "new", tt.a, tt.b)
+			if got != tt.want {
+				t.Fatalf("UnifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortedJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "sorts object keys",
+			input: `{"password":"new","username":"admin"}`,
+			want:  "{\n  \"password\": \"new\",\n  \"username\": \"admin\"\n}",
+		},
+		{
+			name:  "non-JSON passthrough",
+			input: "plain-text-secret",
+			want:  "plain-text-secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sortedJSON(tt.input)
+			if got != tt.want {
+				t.Fatalf("sortedJSON() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// forwardedSignals are the signals the "exec" subcommand relays from its
+// own process to the child it supervises.
+var forwardedSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT}
+
+// ExecConfig holds the parsed arguments for the "exec" subcommand:
+//
+//	aws-ssm exec --secret NAME [--env-prefix PREFIX] [--cache-ttl DURATION] -- cmd [args...]
+type ExecConfig struct {
+	SecretConfig Config // the usual secret-fetch Config (SecretID, Region, Scheme/Ref, CacheTTL, ...)
+	EnvPrefix    string
+	Command      []string
+}
+
+// ParseExecArgs parses "aws-ssm exec ..." arguments. args is the full
+// os.Args slice, with the "exec" subcommand at args[1].
+func ParseExecArgs(args []string) (ExecConfig, error) {
+	if len(args) < 2 || args[1] != "exec" {
+		return ExecConfig{}, fmt.Errorf("expected the \"exec\" subcommand")
+	}
+
+	rest := args[2:]
+	sep := -1
+	for i, a := range rest {
+		if a == "--" {
+			sep = i
+			break
+		}
+	}
+	if sep < 0 {
+		return ExecConfig{}, fmt.Errorf("exec requires a \"--\" separator before the command to run")
+	}
+
+	flags, command := rest[:sep], rest[sep+1:]
+	if len(command) == 0 {
+		return ExecConfig{}, fmt.Errorf("exec requires a command to run after \"--\"")
+	}
+
+	var secretID, envPrefix string
+	var cacheTTL string
+	for i := 0; i < len(flags); i++ {
+		switch flags[i] {
+		case "--secret":
+			if i+1 >= len(flags) {
+				return ExecConfig{}, fmt.Errorf("--secret requires a value")
+			}
+			i++
+			secretID = flags[i]
+		case "--env-prefix":
+			if i+1 >= len(flags) {
+				return ExecConfig{}, fmt.Errorf("--env-prefix requires a value")
+			}
+			i++
+			envPrefix = flags[i]
+		case "--cache-ttl":
+			if i+1 >= len(flags) {
+				return ExecConfig{}, fmt.Errorf("--cache-ttl requires a value")
+			}
+			i++
+			cacheTTL = flags[i]
+		default:
+			return ExecConfig{}, fmt.Errorf("unknown exec flag %q", flags[i])
+		}
+	}
+	if secretID == "" {
+		return ExecConfig{}, fmt.Errorf("--secret is required")
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		return ExecConfig{}, fmt.Errorf("AWS region must be specified via the AWS_REGION environment variable")
+	}
+
+	cfg := Config{SecretID: secretID, Region: region}
+	if scheme, ref, ok := ParseRef(secretID); ok {
+		cfg.Scheme = scheme
+		cfg.Ref = ref
+	}
+	if cacheTTL != "" {
+		parsed, err := time.ParseDuration(cacheTTL)
+		if err != nil {
+			return ExecConfig{}, fmt.Errorf("invalid --cache-ttl %q: %w", cacheTTL, err)
+		}
+		cfg.CacheTTL = parsed
+	}
+
+	return ExecConfig{SecretConfig: cfg, EnvPrefix: envPrefix, Command: command}, nil
+}
+
+// SecretToEnv parses a JSON secret's top-level fields into
+// "PREFIXKEY=value" environment variable assignments, upper-casing each key
+// the way entrypoint tools like envconsul do.
+func SecretToEnv(secretValue, prefix string) ([]string, error) {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(secretValue), &data); err != nil {
+		return nil, fmt.Errorf("exec requires a JSON secret with top-level fields: %w", err)
+	}
+
+	env := make([]string, 0, len(data))
+	for k, v := range data {
+		env = append(env, fmt.Sprintf("%s%s=%s", prefix, strings.ToUpper(k), stringifyJSON(v)))
+	}
+	return env, nil
+}
+
+// RunExec fetches cfg.SecretConfig's secret, injects its fields into the
+// child's environment as cfg.EnvPrefix-prefixed variables, and runs
+// cfg.Command as a supervised child process: signals received by aws-ssm
+// are relayed to the child, and RunExec returns once the child exits.
+func RunExec(ctx context.Context, app *App, cfg ExecConfig) (int, error) {
+	secretValue, err := app.GetSecret(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	env, err := SecretToEnv(secretValue, cfg.EnvPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	cmd := exec.CommandContext(ctx, cfg.Command[0], cfg.Command[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start %q: %w", cfg.Command[0], err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, forwardedSignals...)
+	defer signal.Stop(sigCh)
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	for {
+		select {
+		case sig := <-sigCh:
+			if cmd.Process != nil {
+				_ = cmd.Process.Signal(sig)
+			}
+		case err := <-done:
+			if err == nil {
+				return 0, nil
+			}
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return exitErr.ExitCode(), nil
+			}
+			return 0, fmt.Errorf("failed to run %q: %w", cfg.Command[0], err)
+		}
+	}
+}
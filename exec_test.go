@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+func TestParseExecArgs(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    ExecConfig
+		wantErr bool
+	}{
+		{
+			name: "secret and command",
+			args: []string{"aws-ssm", "exec", "--secret", "my-secret", "--", "mycmd", "arg1"},
+			want: ExecConfig{
+				SecretConfig: Config{SecretID: "my-secret", Region: "us-east-1"},
+				Command:      []string{"mycmd", "arg1"},
+			},
+		},
+		{
+			name: "with env-prefix",
+			args: []string{"aws-ssm", "exec", "--secret", "my-secret", "--env-prefix", "APP_", "--", "mycmd"},
+			want: ExecConfig{
+				SecretConfig: Config{SecretID: "my-secret", Region: "us-east-1"},
+				EnvPrefix:    "APP_",
+				Command:      []string{"mycmd"},
+			},
+		},
+		{
+			name:    "missing -- separator",
+			args:    []string{"aws-ssm", "exec", "--secret", "my-secret"},
+			wantErr: true,
+		},
+		{
+			name:    "missing command after --",
+			args:    []string{"aws-ssm", "exec", "--secret", "my-secret", "--"},
+			wantErr: true,
+		},
+		{
+			name:    "missing --secret",
+			args:    []string{"aws-ssm", "exec", "--", "mycmd"},
+			wantErr: true,
+		},
+		{
+			name:    "not the exec subcommand",
+			args:    []string{"aws-ssm", "my-secret"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseExecArgs(tt.args)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseExecArgs() expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseExecArgs() unexpected error: %v", err)
+			}
+			if got.EnvPrefix != tt.want.EnvPrefix ||
+				got.SecretConfig.SecretID != tt.want.SecretConfig.SecretID ||
+				got.SecretConfig.Region != tt.want.SecretConfig.Region ||
+				strings.Join(got.Command, " ") != strings.Join(tt.want.Command, " ") {
+				t.Fatalf("ParseExecArgs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretToEnv(t *testing.T) {
+	env, err := SecretToEnv(`{"username":"admin","password":"secret123"}`, "APP_")
+	if err != nil {
+		t.Fatalf("SecretToEnv() unexpected error: %v", err)
+	}
+
+	want := map[string]bool{"APP_USERNAME=admin": true, "APP_PASSWORD=secret123": true}
+	for _, kv := range env {
+		if !want[kv] {
+			t.Errorf("SecretToEnv() produced unexpected entry %q", kv)
+		}
+		delete(want, kv)
+	}
+	if len(want) != 0 {
+		t.Errorf("SecretToEnv() missing entries: %v", want)
+	}
+
+	if _, err := SecretToEnv("not-json", ""); err == nil {
+		t.Error("SecretToEnv() with non-JSON secret expected an error, got nil")
+	}
+}
+
+// fakeEnvChild is a tiny real child process ("fake child binary") used to
+// prove RunExec actually injects env vars and propagates exit codes: a
+// shell script, since building a throwaway Go binary per test run would be
+// slow and the repo otherwise has no test helper binaries.
+func fakeEnvChild(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fake-child.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755); err != nil {
+		t.Fatalf("failed to write fake child script: %v", err)
+	}
+	return path
+}
+
+func TestRunExec_InjectsEnvAndReturnsExitCode(t *testing.T) {
+	if _, err := exec.LookPath("/bin/sh"); err != nil {
+		t.Skip("/bin/sh not available")
+	}
+
+	outPath := filepath.Join(t.TempDir(), "out.txt")
+	script := fakeEnvChild(t, `echo "user=$APP_USERNAME pass=$APP_PASSWORD" > "`+outPath+`"
+exit 7
+`)
+
+	mockClient := new(MockSecretsManagerClient)
+	mockClient.On("GetSecretValue", context.Background(), &secretsmanager.GetSecretValueInput{
+		SecretId: stringPtr("my-secret"),
+	}).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: stringPtr(`{"username":"admin","password":"secret123"}`),
+	}, nil)
+
+	app := &App{
+		Client: mockClient,
+		Config: Config{SecretID: "my-secret", Region: "us-east-1"},
+	}
+
+	code, err := RunExec(context.Background(), app, ExecConfig{
+		SecretConfig: app.Config,
+		EnvPrefix:    "APP_",
+		Command:      []string{script},
+	})
+	if err != nil {
+		t.Fatalf("RunExec() unexpected error: %v", err)
+	}
+	if code != 7 {
+		t.Fatalf("RunExec() exit code = %d, want 7", code)
+	}
+
+	out, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("failed to read fake child output: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "user=admin pass=secret123" {
+		t.Fatalf("fake child saw %q, want %q", got, "user=admin pass=secret123")
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"github.com/hypolas/aws-ssm-light/filecache"
+)
+
+// fileCachingSecretsManagerClient wraps a SecretsManagerClient with an
+// at-rest encrypted cache file (see the filecache package), so a recent
+// secret value can be served without reaching AWS at all. It's driven by
+// --cache-file and reuses --cache-ttl for freshness, the same way
+// cachingSecretsManagerClient reuses it for the in-memory cache.
+type fileCachingSecretsManagerClient struct {
+	client SecretsManagerClient
+	cache  *filecache.Cache
+}
+
+// newFileCachingSecretsManagerClient opens cfg.CacheFile and wraps client
+// with it. The encryption key is derived (via filecache.DeriveKey) from
+// cfg.CacheKey if set, otherwise from AWS_ACCESS_KEY_ID+cfg.Region.
+func newFileCachingSecretsManagerClient(client SecretsManagerClient, cfg Config) (*fileCachingSecretsManagerClient, error) {
+	keyMaterial := cfg.CacheKey
+	if keyMaterial == "" {
+		accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+		if accessKeyID == "" {
+			return nil, fmt.Errorf("--cache-file requires AWS_ACCESS_KEY_ID to be set, or an explicit --cache-key")
+		}
+		keyMaterial = accessKeyID + cfg.Region
+	}
+
+	store, err := filecache.Open(cfg.CacheFile, cfg.CacheTTL, []byte(keyMaterial))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open --cache-file %q: %w", cfg.CacheFile, err)
+	}
+	return &fileCachingSecretsManagerClient{client: client, cache: store}, nil
+}
+
+// GetSecretValue implements SecretsManagerClient. As with
+// cachingSecretsManagerClient, lookups pinned to a specific VersionId
+// bypass the cache.
+func (c *fileCachingSecretsManagerClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	if params.VersionId != nil {
+		return c.client.GetSecretValue(ctx, params, optFns...)
+	}
+
+	stage := ""
+	if params.VersionStage != nil {
+		stage = *params.VersionStage
+	}
+	key := filecache.Key{SecretID: strPtrValue(params.SecretId), Stage: stage}
+
+	if value, ok := c.cache.Get(key); ok {
+		return &secretsmanager.GetSecretValueOutput{SecretString: &value}, nil
+	}
+
+	result, err := c.client.GetSecretValue(ctx, params, optFns...)
+	if err != nil {
+		return nil, err
+	}
+	if result.SecretString != nil {
+		if err := c.cache.Put(key, *result.SecretString); err != nil {
+			return nil, fmt.Errorf("failed to update --cache-file: %w", err)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,220 @@
+// Package filecache implements an at-rest encrypted cache of fetched
+// secrets, so aws-ssm-light can serve a recent value without reaching AWS
+// (useful for offline or air-gapped reruns). Unlike the in-memory cache
+// package, entries survive process restarts: they are persisted as an
+// AES-256-GCM encrypted JSON index on disk.
+package filecache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Key identifies a cached secret by ID and version stage, mirroring the
+// cache package's key shape.
+type Key struct {
+	SecretID string
+	Stage    string
+}
+
+// record is the on-disk, per-entry representation: the secret value
+// encrypted with a random nonce, plus when it was fetched.
+type record struct {
+	SecretID   string    `json:"secretId"`
+	Stage      string    `json:"versionStage"`
+	Nonce      []byte    `json:"nonce"`
+	Ciphertext []byte    `json:"ciphertext"`
+	FetchedAt  time.Time `json:"fetchedAt"`
+}
+
+// fileFormat is the top-level shape of the cache file.
+type fileFormat struct {
+	Version int      `json:"version"`
+	Records []record `json:"records"`
+}
+
+const fileFormatVersion = 1
+
+// Cache is an encrypted, disk-backed cache of secret values, keyed by Key.
+type Cache struct {
+	path    string
+	ttl     time.Duration
+	aead    cipher.AEAD
+	mu      sync.Mutex
+	records map[Key]record
+}
+
+// Open loads (or initializes) the cache file at path, encrypting and
+// decrypting entries with an AES-256-GCM key derived from keyMaterial via
+// DeriveKey. ttl <= 0 means entries are never considered fresh: Get always
+// misses, though Put still persists them (e.g. for later offline use).
+//
+// A cache file that fails to parse as JSON is treated the same as a
+// missing one: the on-disk index is discarded and rebuilt from scratch, so
+// one corrupted file doesn't make aws-ssm-light unusable.
+func Open(path string, ttl time.Duration, keyMaterial []byte) (*Cache, error) {
+	block, err := aes.NewCipher(DeriveKey(keyMaterial))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cache cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cache cipher: %w", err)
+	}
+
+	c := &Cache{
+		path:    path,
+		ttl:     ttl,
+		aead:    aead,
+		records: make(map[Key]record),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("failed to read cache file %q: %w", path, err)
+	}
+
+	var parsed fileFormat
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return c, nil
+	}
+	for _, rec := range parsed.Records {
+		c.records[Key{SecretID: rec.SecretID, Stage: rec.Stage}] = rec
+	}
+	return c, nil
+}
+
+// Get returns the decrypted value for key if a fresh, decryptable entry
+// exists. A stale entry (older than ttl) is reported as a miss but kept on
+// disk until the next Put rotates it away. A tampered or corrupted entry
+// (GCM authentication failure) is discarded immediately and reported as a
+// miss, so the caller naturally re-fetches and overwrites it.
+func (c *Cache) Get(key Key) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rec, ok := c.records[key]
+	if !ok {
+		return "", false
+	}
+
+	plaintext, err := c.aead.Open(nil, rec.Nonce, rec.Ciphertext, nil)
+	if err != nil {
+		delete(c.records, key)
+		_ = c.persistLocked()
+		return "", false
+	}
+
+	if c.ttl <= 0 || time.Since(rec.FetchedAt) > c.ttl {
+		return "", false
+	}
+
+	return string(plaintext), true
+}
+
+// Put encrypts value under a fresh random nonce, stores it under key,
+// rotates out any entry older than ttl, and persists the index to disk.
+func (c *Cache) Put(key Key, value string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate cache nonce: %w", err)
+	}
+	ciphertext := c.aead.Seal(nil, nonce, []byte(value), nil)
+
+	c.records[key] = record{
+		SecretID:   key.SecretID,
+		Stage:      key.Stage,
+		Nonce:      nonce,
+		Ciphertext: ciphertext,
+		FetchedAt:  time.Now(),
+	}
+
+	if c.ttl > 0 {
+		for k, rec := range c.records {
+			if k != key && time.Since(rec.FetchedAt) > c.ttl {
+				delete(c.records, k)
+			}
+		}
+	}
+
+	return c.persistLocked()
+}
+
+// persistLocked writes the current record set to c.path. The caller must
+// hold c.mu. Writes go to a temp file in the same directory followed by a
+// rename, so a crash mid-write can't leave a half-written cache file.
+func (c *Cache) persistLocked() error {
+	records := make([]record, 0, len(c.records))
+	for _, rec := range c.records {
+		records = append(records, rec)
+	}
+
+	data, err := json.MarshalIndent(fileFormat{Version: fileFormatVersion, Records: records}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode cache file: %w", err)
+	}
+
+	dir := filepath.Dir(c.path)
+	tmp, err := os.CreateTemp(dir, ".aws-ssm-cache-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache file %q: %w", c.path, err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file %q: %w", c.path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file %q: %w", c.path, err)
+	}
+	if err := os.Rename(tmpPath, c.path); err != nil {
+		return fmt.Errorf("failed to write cache file %q: %w", c.path, err)
+	}
+	return nil
+}
+
+// DeriveKey derives a 32-byte AES-256 key from keyMaterial using
+// HKDF-SHA256 (RFC 5869), implemented directly over crypto/hmac to avoid
+// pulling in a third-party crypto package for a handful of lines.
+func DeriveKey(keyMaterial []byte) []byte {
+	const (
+		salt = "aws-ssm-light/filecache/v1"
+		info = "aes-256-gcm-key"
+		size = 32
+	)
+
+	extract := hmac.New(sha256.New, []byte(salt))
+	extract.Write(keyMaterial)
+	prk := extract.Sum(nil)
+
+	var (
+		okm  []byte
+		prev []byte
+	)
+	for counter := byte(1); len(okm) < size; counter++ {
+		expand := hmac.New(sha256.New, prk)
+		expand.Write(prev)
+		expand.Write([]byte(info))
+		expand.Write([]byte{counter})
+		prev = expand.Sum(nil)
+		okm = append(okm, prev...)
+	}
+	return okm[:size]
+}
@@ -0,0 +1,155 @@
+package filecache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCache_PutThenGet(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	c, err := Open(path, time.Minute, []byte("key-material"))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+
+	key := Key{SecretID: "my-secret", Stage: "AWSCURRENT"}
+	if err := c.Put(key, "super-secret-value"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	got, ok := c.Get(key)
+	if !ok {
+		t.Fatalf("Get() = (_, false), want a fresh hit")
+	}
+	if got != "super-secret-value" {
+		t.Fatalf("Get() = %q, want %q", got, "super-secret-value")
+	}
+}
+
+func TestCache_PersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key := Key{SecretID: "my-secret", Stage: "AWSCURRENT"}
+
+	c1, err := Open(path, time.Minute, []byte("key-material"))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if err := c1.Put(key, "persisted-value"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	c2, err := Open(path, time.Minute, []byte("key-material"))
+	if err != nil {
+		t.Fatalf("second Open() unexpected error: %v", err)
+	}
+	got, ok := c2.Get(key)
+	if !ok || got != "persisted-value" {
+		t.Fatalf("Get() after reopen = (%q, %v), want (%q, true)", got, ok, "persisted-value")
+	}
+}
+
+func TestCache_TTLExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key := Key{SecretID: "my-secret", Stage: "AWSCURRENT"}
+
+	c, err := Open(path, time.Millisecond, []byte("key-material"))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if err := c.Put(key, "value"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get(key); ok {
+		t.Fatal("Get() = (_, true), want a miss after ttl expiry")
+	}
+}
+
+func TestCache_ZeroTTLNeverHits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key := Key{SecretID: "my-secret", Stage: "AWSCURRENT"}
+
+	c, err := Open(path, 0, []byte("key-material"))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if err := c.Put(key, "value"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	if _, ok := c.Get(key); ok {
+		t.Fatal("Get() = (_, true), want a miss when ttl <= 0")
+	}
+}
+
+func TestCache_TamperDetection(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key := Key{SecretID: "my-secret", Stage: "AWSCURRENT"}
+
+	c, err := Open(path, time.Minute, []byte("key-material"))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if err := c.Put(key, "value"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	// Flip a byte of the stored ciphertext directly, simulating on-disk
+	// tampering or corruption, then reopen so the cache re-reads the file.
+	rec := c.records[key]
+	rec.Ciphertext[0] ^= 0xFF
+	c.records[key] = rec
+
+	if _, ok := c.Get(key); ok {
+		t.Fatal("Get() = (_, true), want a miss for a tampered entry")
+	}
+	if _, stillPresent := c.records[key]; stillPresent {
+		t.Fatal("tampered entry should be discarded from the in-memory index after a failed Get")
+	}
+}
+
+func TestCache_KeyChangeInvalidatesAllEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key1 := Key{SecretID: "secret-a", Stage: "AWSCURRENT"}
+	key2 := Key{SecretID: "secret-b", Stage: "AWSCURRENT"}
+
+	c1, err := Open(path, time.Minute, []byte("original-key"))
+	if err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if err := c1.Put(key1, "value-a"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	if err := c1.Put(key2, "value-b"); err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+
+	c2, err := Open(path, time.Minute, []byte("different-key"))
+	if err != nil {
+		t.Fatalf("second Open() unexpected error: %v", err)
+	}
+
+	if _, ok := c2.Get(key1); ok {
+		t.Fatal("Get(key1) = (_, true), want a miss once the derived key has changed")
+	}
+	if _, ok := c2.Get(key2); ok {
+		t.Fatal("Get(key2) = (_, true), want a miss once the derived key has changed")
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	a := DeriveKey([]byte("access-key-id-us-east-1"))
+	b := DeriveKey([]byte("access-key-id-us-east-1"))
+	c := DeriveKey([]byte("different-access-key-us-east-1"))
+
+	if len(a) != 32 {
+		t.Fatalf("DeriveKey() returned %d bytes, want 32", len(a))
+	}
+	if string(a) != string(b) {
+		t.Fatal("DeriveKey() is not deterministic for identical input")
+	}
+	if string(a) == string(c) {
+		t.Fatal("DeriveKey() produced the same key for different input")
+	}
+}
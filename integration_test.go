@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -191,6 +193,38 @@ func BenchmarkGetSecret(b *testing.B) {
 	}
 }
 
+// BenchmarkGetSecrets benchmarks concurrent batch retrieval against the
+// same sequential workload BenchmarkGetSecret exercises, to show the
+// bounded worker pool scales instead of fetching one secret at a time.
+func BenchmarkGetSecrets(b *testing.B) {
+	const numSecrets = 20
+
+	mockClient := new(MockSecretsManagerClient)
+	ids := make([]string, numSecrets)
+	for i := 0; i < numSecrets; i++ {
+		id := fmt.Sprintf("benchmark-secret-%d", i)
+		ids[i] = id
+		mockClient.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{
+			SecretId: aws.String(id),
+		}).Return(&secretsmanager.GetSecretValueOutput{
+			SecretString: aws.String("benchmark-secret-value"),
+		}, nil)
+	}
+
+	app := &App{
+		Client: mockClient,
+		Config: Config{Region: "us-east-1"},
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, errs := app.GetSecrets(context.Background(), ids, 8)
+		if len(errs) != 0 {
+			b.Fatalf("unexpected errors: %v", errs)
+		}
+	}
+}
+
 // BenchmarkFormatOutput benchmarks the output formatting
 func BenchmarkFormatOutput(b *testing.B) {
 	tests := []struct {
@@ -4,14 +4,41 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 )
 
+// defaultVersionStage is the AWS Secrets Manager staging label used when
+// neither --version-id nor --version-stage is specified.
+const defaultVersionStage = "AWSCURRENT"
+
+// maskingWriter wraps an io.Writer and replaces every occurrence of secret
+// with "***MASKED***" before writing. Used by --mask to keep the fetched
+// secret value out of stderr diagnostics.
+type maskingWriter struct {
+	w      io.Writer
+	secret string
+}
+
+func (m *maskingWriter) Write(p []byte) (int, error) {
+	if m.secret == "" {
+		return m.w.Write(p)
+	}
+	masked := strings.ReplaceAll(string(p), m.secret, "***MASKED***")
+	if _, err := m.w.Write([]byte(masked)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 // Version information - set during build with -ldflags
 var (
 	Version    = "dev"
@@ -29,12 +56,71 @@ type SecretsManagerClient interface {
 type Config struct {
 	SecretID string
 	Region   string
+
+	// Scheme and Ref are populated when SecretID is a "scheme://ref"
+	// provider reference (e.g. "vault://secret/myapp#password"). Scheme is
+	// empty for a bare AWS Secrets Manager ID or ARN, the original
+	// aws-ssm-light behavior.
+	Scheme string
+	Ref    string
+
+	// VersionID and VersionStage select which AWS Secrets Manager version
+	// to fetch. At most one may be set; when both are empty GetSecret uses
+	// defaultVersionStage ("AWSCURRENT"). They are ignored by the
+	// scheme-based Provider path, which has no notion of AWS SM versions.
+	VersionID    string
+	VersionStage string
+
+	// Diff, when set, fetches both AWSCURRENT and AWSPREVIOUS and prints a
+	// unified diff of the two instead of the secret value.
+	Diff bool
+
+	// Key, when set, plucks a single dotted-path field out of a JSON
+	// secret (e.g. "database.password") before rendering, like `jq -r`.
+	Key string
+
+	// Format selects the output renderer: "" (default, same as "raw"),
+	// "raw", "json", "env", "dotenv", "yaml", or "template". Template is
+	// rendered from the Go text/template in Template.
+	Format   string
+	Template string
+
+	// Mask, when set, redacts the fetched secret value out of any
+	// diagnostic output written to stderr.
+	Mask bool
+
+	// CacheTTL, when > 0, wraps the Secrets Manager client in an in-memory
+	// cache (see cachingSecretsManagerClient) so repeated fetches of the
+	// same SecretID+VersionStage within the TTL skip the network call.
+	CacheTTL time.Duration
+
+	// SecretIDs, when non-empty, switches aws-ssm into batch mode: every ID
+	// (or provider reference) is fetched concurrently via App.GetSecrets and
+	// the result is printed as a single aggregate JSON object instead of
+	// using SecretID/Scheme/Ref. Populated from multiple positional
+	// arguments or an "@file" argument (one ID per line).
+	SecretIDs []string
+
+	// MaxConcurrency bounds how many secrets App.GetSecrets fetches at once
+	// in batch mode.
+	MaxConcurrency int
+
+	// CacheFile, when set, persists fetched secrets to this path as an
+	// AES-256-GCM encrypted index (see the filecache package), so a fresh
+	// value (per CacheTTL) can be served without reaching AWS at all.
+	CacheFile string
+
+	// CacheKey overrides the key material the --cache-file encryption key
+	// is derived from. When empty, it defaults to
+	// AWS_ACCESS_KEY_ID+Region.
+	CacheKey string
 }
 
 // App holds the application dependencies
 type App struct {
-	Client SecretsManagerClient
-	Config Config
+	Client   SecretsManagerClient
+	Provider Provider
+	Config   Config
 }
 
 // ShowVersion displays version information
@@ -56,14 +142,43 @@ func ShowVersion() {
 // ShowUsage displays usage information
 func ShowUsage(progName string) {
 	fmt.Fprintf(os.Stderr, "Usage: %s <secret-id> [region]\n", progName)
+	fmt.Fprintf(os.Stderr, "       %s <secret-id> <secret-id>...\n", progName)
+	fmt.Fprintf(os.Stderr, "       %s @secret-ids.txt\n", progName)
 	fmt.Fprintf(os.Stderr, "       %s --version\n", progName)
 	fmt.Fprintf(os.Stderr, "       %s --help\n", progName)
 	fmt.Fprintf(os.Stderr, "\nArguments:\n")
-	fmt.Fprintf(os.Stderr, "  secret-id    AWS Secrets Manager secret ID or ARN\n")
-	fmt.Fprintf(os.Stderr, "  region       AWS region (optional, overrides AWS_REGION)\n")
+	fmt.Fprintf(os.Stderr, "  secret-id    AWS Secrets Manager secret ID or ARN, or a provider\n")
+	fmt.Fprintf(os.Stderr, "               reference of the form \"scheme://ref\":\n")
+	fmt.Fprintf(os.Stderr, "                 aws-sm://name            AWS Secrets Manager\n")
+	fmt.Fprintf(os.Stderr, "                 aws-ssm://path           AWS SSM Parameter Store\n")
+	fmt.Fprintf(os.Stderr, "                 vault://mount/path#key   HashiCorp Vault (KV v2)\n")
+	fmt.Fprintf(os.Stderr, "                 azkv://vault/name        Azure Key Vault\n")
+	fmt.Fprintf(os.Stderr, "                 gcpsm://project/name     GCP Secret Manager\n")
+	fmt.Fprintf(os.Stderr, "               Multiple secret-ids (or an \"@file\" with one ID per\n")
+	fmt.Fprintf(os.Stderr, "               line) fetch concurrently and print a single JSON\n")
+	fmt.Fprintf(os.Stderr, "               object of {\"id\": value} instead of a raw value.\n")
+	fmt.Fprintf(os.Stderr, "               --format, --key, --diff, --mask, --version-id, and\n")
+	fmt.Fprintf(os.Stderr, "               --version-stage only apply to a single secret-id and\n")
+	fmt.Fprintf(os.Stderr, "               cannot be combined with batch retrieval.\n")
+	fmt.Fprintf(os.Stderr, "  region       AWS region (optional, overrides AWS_REGION; single\n")
+	fmt.Fprintf(os.Stderr, "               secret-id only, not combined with batch retrieval)\n")
 	fmt.Fprintf(os.Stderr, "\nOptions:\n")
-	fmt.Fprintf(os.Stderr, "  --version    Show version information\n")
-	fmt.Fprintf(os.Stderr, "  --help       Show this help message\n")
+	fmt.Fprintf(os.Stderr, "  --version              Show version information\n")
+	fmt.Fprintf(os.Stderr, "  --help                 Show this help message\n")
+	fmt.Fprintf(os.Stderr, "  --version-id ID        Fetch a specific AWS Secrets Manager version\n")
+	fmt.Fprintf(os.Stderr, "  --version-stage STAGE  Fetch a specific version stage (default: AWSCURRENT)\n")
+	fmt.Fprintf(os.Stderr, "  --diff                 Print a unified diff between AWSCURRENT and AWSPREVIOUS\n")
+	fmt.Fprintf(os.Stderr, "  --key PATH             Pluck a single field from a JSON secret (e.g. db.password)\n")
+	fmt.Fprintf(os.Stderr, "  --format FORMAT        Render as raw|json|env|dotenv|yaml|template (default: raw)\n")
+	fmt.Fprintf(os.Stderr, "  --template TEMPLATE    Go text/template body, used with --format template\n")
+	fmt.Fprintf(os.Stderr, "  --mask                 Redact the secret value from diagnostic output\n")
+	fmt.Fprintf(os.Stderr, "  --cache-ttl DURATION   Cache fetched secrets in memory for DURATION (e.g. 5m)\n")
+	fmt.Fprintf(os.Stderr, "  --max-concurrency N    Bound concurrent fetches in batch mode (default: 5)\n")
+	fmt.Fprintf(os.Stderr, "  --cache-file PATH      Persist fetched secrets to PATH, AES-256-GCM encrypted\n")
+	fmt.Fprintf(os.Stderr, "  --cache-key KEY        Key material for --cache-file (default: AWS_ACCESS_KEY_ID+region)\n")
+	fmt.Fprintf(os.Stderr, "\nSubcommands:\n")
+	fmt.Fprintf(os.Stderr, "  %s exec --secret NAME [--env-prefix PREFIX] [--cache-ttl DURATION] -- cmd [args...]\n", progName)
+	fmt.Fprintf(os.Stderr, "               Fetch a secret, inject its JSON fields as env vars, and run cmd\n")
 	fmt.Fprintf(os.Stderr, "\nEnvironment variables:\n")
 	fmt.Fprintf(os.Stderr, "  AWS_REGION: AWS region (can be overridden by second argument)\n")
 	fmt.Fprintf(os.Stderr, "  AWS_ACCESS_KEY_ID: AWS access key\n")
@@ -89,29 +204,360 @@ func ParseArgs(args []string) (Config, error) {
 		os.Exit(0)
 	}
 
-	secretID := args[1]
-	region := os.Getenv("AWS_REGION")
+	var positional []string
+	var versionID, versionStage, key, format, tmpl string
+	var diff, mask bool
+	var cacheTTL time.Duration
+	var cacheFile, cacheKey string
+	maxConcurrency := 5
+
+	rest := args[1:]
+	for i := 0; i < len(rest); i++ {
+		switch rest[i] {
+		case "--cache-ttl":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--cache-ttl requires a value")
+			}
+			i++
+			parsed, err := time.ParseDuration(rest[i])
+			if err != nil {
+				return Config{}, fmt.Errorf("invalid --cache-ttl %q: %w", rest[i], err)
+			}
+			cacheTTL = parsed
+		case "--cache-file":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--cache-file requires a value")
+			}
+			i++
+			cacheFile = rest[i]
+		case "--cache-key":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--cache-key requires a value")
+			}
+			i++
+			cacheKey = rest[i]
+		case "--max-concurrency":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--max-concurrency requires a value")
+			}
+			i++
+			n, err := strconv.Atoi(rest[i])
+			if err != nil || n <= 0 {
+				return Config{}, fmt.Errorf("invalid --max-concurrency %q: must be a positive integer", rest[i])
+			}
+			maxConcurrency = n
+		case "--version-id":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--version-id requires a value")
+			}
+			i++
+			versionID = rest[i]
+		case "--version-stage":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--version-stage requires a value")
+			}
+			i++
+			versionStage = rest[i]
+		case "--diff":
+			diff = true
+		case "--key":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--key requires a value")
+			}
+			i++
+			key = rest[i]
+		case "--format":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--format requires a value")
+			}
+			i++
+			format = rest[i]
+		case "--template":
+			if i+1 >= len(rest) {
+				return Config{}, fmt.Errorf("--template requires a value")
+			}
+			i++
+			tmpl = rest[i]
+		case "--mask":
+			mask = true
+		default:
+			positional = append(positional, rest[i])
+		}
+	}
+
+	if len(positional) == 0 {
+		return Config{}, fmt.Errorf("insufficient arguments")
+	}
+	if versionID != "" && versionStage != "" {
+		return Config{}, fmt.Errorf("--version-id and --version-stage cannot be used together")
+	}
+	if !supportedFormats[format] {
+		return Config{}, fmt.Errorf("unsupported --format %q", format)
+	}
+	if format == "template" && tmpl == "" {
+		return Config{}, fmt.Errorf("--format template requires --template")
+	}
 
-	// Override region if provided as argument
-	if len(args) > 2 {
-		region = args[2]
+	var secretIDs []string
+	batch := false
+	if len(positional) == 1 && strings.HasPrefix(positional[0], "@") {
+		ids, err := readSecretIDsFile(strings.TrimPrefix(positional[0], "@"))
+		if err != nil {
+			return Config{}, err
+		}
+		secretIDs = ids
+		batch = true
+	} else if len(positional) > 2 {
+		secretIDs = positional
+		batch = true
 	}
 
-	if region == "" {
-		return Config{}, fmt.Errorf("AWS region must be specified either via AWS_REGION environment variable or as second argument")
+	var secretID, region string
+	if batch {
+		region = os.Getenv("AWS_REGION")
+		if region == "" {
+			return Config{}, fmt.Errorf("AWS region must be specified via AWS_REGION environment variable for batch retrieval")
+		}
+	} else {
+		secretID = positional[0]
+		region = os.Getenv("AWS_REGION")
+
+		// Override region if provided as argument
+		if len(positional) > 1 {
+			region = positional[1]
+		}
+
+		if region == "" {
+			return Config{}, fmt.Errorf("AWS region must be specified either via AWS_REGION environment variable or as second argument")
+		}
 	}
 
-	return Config{
-		SecretID: secretID,
-		Region:   region,
-	}, nil
+	if batch {
+		if format != "" && format != "raw" {
+			return Config{}, fmt.Errorf("--format cannot be combined with batch secret retrieval")
+		}
+		if key != "" {
+			return Config{}, fmt.Errorf("--key cannot be combined with batch secret retrieval")
+		}
+		if diff {
+			return Config{}, fmt.Errorf("--diff cannot be combined with batch secret retrieval")
+		}
+		if mask {
+			return Config{}, fmt.Errorf("--mask cannot be combined with batch secret retrieval")
+		}
+		if versionID != "" {
+			return Config{}, fmt.Errorf("--version-id cannot be combined with batch secret retrieval")
+		}
+		if versionStage != "" {
+			return Config{}, fmt.Errorf("--version-stage cannot be combined with batch secret retrieval")
+		}
+	}
+
+	cfg := Config{
+		SecretID:       secretID,
+		SecretIDs:      secretIDs,
+		Region:         region,
+		VersionID:      versionID,
+		VersionStage:   versionStage,
+		Diff:           diff,
+		Key:            key,
+		Format:         format,
+		Template:       tmpl,
+		Mask:           mask,
+		CacheTTL:       cacheTTL,
+		MaxConcurrency: maxConcurrency,
+		CacheFile:      cacheFile,
+		CacheKey:       cacheKey,
+	}
+
+	if !batch {
+		if scheme, ref, ok := ParseRef(secretID); ok {
+			cfg.Scheme = scheme
+			cfg.Ref = ref
+		}
+	}
+
+	return cfg, nil
+}
+
+// readSecretIDsFile reads one secret ID per line from path, the target of an
+// "@path" batch argument. Blank lines and lines starting with "#" are
+// skipped.
+func readSecretIDsFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret ID file %q: %w", path, err)
+	}
+
+	var ids []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("secret ID file %q contains no secret IDs", path)
+	}
+	return ids, nil
 }
 
-// GetSecret retrieves a secret from AWS Secrets Manager
+// GetSecret retrieves a secret. If Config.Scheme is set (a "scheme://ref"
+// reference was passed on the command line), it is fetched through
+// app.Provider; otherwise it falls back to the original behavior of
+// fetching app.Config.SecretID directly from AWS Secrets Manager.
 func (app *App) GetSecret(ctx context.Context) (string, error) {
+	if app.Config.Scheme != "" {
+		secret, err := app.Provider.GetSecret(ctx, app.Config.Ref)
+		if err != nil {
+			return "", err
+		}
+		return secret.Value, nil
+	}
+
+	// Leave VersionId/VersionStage unset unless the caller asked for a
+	// specific one: Secrets Manager already defaults to AWSCURRENT (see
+	// defaultVersionStage) when neither is supplied.
 	input := &secretsmanager.GetSecretValueInput{
 		SecretId: &app.Config.SecretID,
 	}
+	if app.Config.VersionID != "" {
+		input.VersionId = &app.Config.VersionID
+	}
+	if app.Config.VersionStage != "" {
+		input.VersionStage = &app.Config.VersionStage
+	}
+
+	result, err := app.Client.GetSecretValue(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret value: %w", err)
+	}
+
+	if result.SecretString == nil {
+		return "", fmt.Errorf("secret does not contain a string value")
+	}
+
+	return *result.SecretString, nil
+}
+
+// GetSecrets fetches multiple secret IDs (plain AWS Secrets Manager IDs or
+// "scheme://ref" provider references) concurrently, bounded by
+// maxConcurrency workers (a value <= 0 is treated as unbounded). It returns
+// the successfully fetched values and, separately, one error per ID that
+// failed, so a single bad secret doesn't abort the rest of the batch.
+func (app *App) GetSecrets(ctx context.Context, ids []string, maxConcurrency int) (map[string]string, map[string]error) {
+	values := make(map[string]string, len(ids))
+	errs := make(map[string]error)
+	if len(ids) == 0 {
+		return values, errs
+	}
+
+	workers := maxConcurrency
+	if workers <= 0 || workers > len(ids) {
+		workers = len(ids)
+	}
+
+	var providersMu sync.Mutex
+	providers := make(map[string]Provider)
+	providerFor := func(scheme string) (Provider, error) {
+		providersMu.Lock()
+		defer providersMu.Unlock()
+		if p, ok := providers[scheme]; ok {
+			return p, nil
+		}
+		factory, err := lookupProvider(scheme)
+		if err != nil {
+			return nil, err
+		}
+		provider, err := factory(app.Config.Region)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize %s provider: %w", scheme, err)
+		}
+		providers[scheme] = provider
+		return provider, nil
+	}
+
+	type result struct {
+		id    string
+		value string
+		err   error
+	}
+
+	jobs := make(chan string)
+	results := make(chan result, len(ids))
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				value, err := app.fetchSecretByID(ctx, id, providerFor)
+				results <- result{id: id, value: value, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, id := range ids {
+			jobs <- id
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		if r.err != nil {
+			errs[r.id] = r.err
+			continue
+		}
+		values[r.id] = r.value
+	}
+	return values, errs
+}
+
+// fetchSecretByID fetches a single secret for GetSecrets: a plain ID goes
+// straight to app.Client, while a "scheme://ref" reference is resolved
+// through a provider obtained from providerFor (which caches one provider
+// instance per scheme across the whole batch).
+func (app *App) fetchSecretByID(ctx context.Context, id string, providerFor func(string) (Provider, error)) (string, error) {
+	if scheme, ref, ok := ParseRef(id); ok {
+		provider, err := providerFor(scheme)
+		if err != nil {
+			return "", err
+		}
+		secret, err := provider.GetSecret(ctx, ref)
+		if err != nil {
+			return "", err
+		}
+		return secret.Value, nil
+	}
+
+	input := &secretsmanager.GetSecretValueInput{SecretId: &id}
+	result, err := app.Client.GetSecretValue(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret value: %w", err)
+	}
+	if result.SecretString == nil {
+		return "", fmt.Errorf("secret does not contain a string value")
+	}
+	return *result.SecretString, nil
+}
+
+// getSecretStage retrieves the legacy AWS Secrets Manager secret at a
+// specific version stage (AWSCURRENT, AWSPREVIOUS, ...), bypassing the
+// Config.VersionID/VersionStage selectors. It is used by GetSecretDiff to
+// fetch both sides of the comparison regardless of what the caller asked for.
+func (app *App) getSecretStage(ctx context.Context, stage string) (string, error) {
+	input := &secretsmanager.GetSecretValueInput{
+		SecretId:     &app.Config.SecretID,
+		VersionStage: &stage,
+	}
 
 	result, err := app.Client.GetSecretValue(ctx, input)
 	if err != nil {
@@ -125,6 +571,38 @@ func (app *App) GetSecret(ctx context.Context) (string, error) {
 	return *result.SecretString, nil
 }
 
+// GetSecretDiff fetches the AWSCURRENT and AWSPREVIOUS versions of the
+// legacy AWS Secrets Manager secret and returns a unified diff between their
+// JSON payloads (object keys sorted) so operators can audit what a rotation
+// changed before promoting it.
+func (app *App) GetSecretDiff(ctx context.Context) (string, error) {
+	previous, err := app.getSecretStage(ctx, "AWSPREVIOUS")
+	if err != nil {
+		return "", fmt.Errorf("failed to get AWSPREVIOUS version: %w", err)
+	}
+	current, err := app.getSecretStage(ctx, defaultVersionStage)
+	if err != nil {
+		return "", fmt.Errorf("failed to get AWSCURRENT version: %w", err)
+	}
+	return UnifiedDiff("AWSPREVIOUS", "AWSCURRENT", sortedJSON(previous), sortedJSON(current)), nil
+}
+
+// sortedJSON re-marshals raw with object keys sorted (encoding/json already
+// sorts map keys, so this just round-trips through an interface{}) for
+// stable diffing of two secret versions. If raw is not valid JSON it is
+// returned unchanged.
+func sortedJSON(raw string) string {
+	var data interface{}
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return raw
+	}
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return raw
+	}
+	return string(out)
+}
+
 // FormatOutput formats the secret output (handles JSON detection)
 func FormatOutput(secretValue string) string {
 	// Try to parse as JSON first, if it fails, output as is
@@ -147,16 +625,56 @@ func NewApp(cfg Config) (*App, error) {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	// Create Secrets Manager client
-	client := secretsmanager.NewFromConfig(awsCfg)
+	// Create Secrets Manager client. The file cache wraps the real client
+	// first (it's the one that should persist across process restarts),
+	// then the in-memory cache wraps that to skip even the decrypt+parse
+	// cost for repeated calls within a single run.
+	var client SecretsManagerClient = secretsmanager.NewFromConfig(awsCfg)
+	if cfg.CacheFile != "" {
+		client, err = newFileCachingSecretsManagerClient(client, cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if cfg.CacheTTL > 0 {
+		client = newCachingSecretsManagerClient(client, cfg.CacheTTL)
+	}
 
-	return &App{
+	app := &App{
 		Client: client,
 		Config: cfg,
-	}, nil
+	}
+
+	if cfg.Scheme != "" {
+		if cfg.Scheme == "aws-sm" {
+			// "aws-sm://name" is the scheme spelling of the same AWS Secrets
+			// Manager backend the bare-ID path uses: reuse the client built
+			// above directly instead of going through the registry, so
+			// --cache-ttl/--cache-file apply here too instead of silently
+			// being ignored behind a second, uncached client.
+			app.Provider = &awsSecretsManagerProvider{client: client}
+		} else {
+			factory, err := lookupProvider(cfg.Scheme)
+			if err != nil {
+				return nil, err
+			}
+			provider, err := factory(cfg.Region)
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize %s provider: %w", cfg.Scheme, err)
+			}
+			app.Provider = provider
+		}
+	}
+
+	return app, nil
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "exec" {
+		mainExec()
+		return
+	}
+
 	cfg, err := ParseArgs(os.Args)
 	if err != nil {
 		if err.Error() == "insufficient arguments" {
@@ -172,11 +690,71 @@ func main() {
 		log.Fatalf("Failed to initialize application: %v", err)
 	}
 
+	if len(cfg.SecretIDs) > 0 {
+		values, errs := app.GetSecrets(context.TODO(), cfg.SecretIDs, cfg.MaxConcurrency)
+		for id, err := range errs {
+			fmt.Fprintf(os.Stderr, "Error: failed to get secret %q: %v\n", id, err)
+		}
+		output, err := json.Marshal(values)
+		if err != nil {
+			log.Fatalf("Failed to render batch output: %v", err)
+		}
+		fmt.Println(string(output))
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if cfg.Diff {
+		diff, err := app.GetSecretDiff(context.TODO())
+		if err != nil {
+			log.Fatalf("Failed to get secret diff: %v", err)
+		}
+		fmt.Print(diff)
+		return
+	}
+
 	secretValue, err := app.GetSecret(context.TODO())
 	if err != nil {
 		log.Fatalf("Failed to get secret: %v", err)
 	}
 
-	output := FormatOutput(secretValue)
+	if cfg.Mask {
+		log.SetOutput(&maskingWriter{w: os.Stderr, secret: secretValue})
+	}
+
+	if cfg.Key != "" {
+		secretValue, err = ExtractKey(secretValue, cfg.Key)
+		if err != nil {
+			log.Fatalf("Failed to extract --key %q: %v", cfg.Key, err)
+		}
+	}
+
+	output, err := RenderOutput(secretValue, cfg.Format, cfg.Template)
+	if err != nil {
+		log.Fatalf("Failed to render output: %v", err)
+	}
 	fmt.Print(output)
 }
+
+// mainExec handles the "aws-ssm exec --secret NAME [...] -- cmd [args...]"
+// subcommand: fetch a secret, inject it as env vars, and run cmd.
+func mainExec() {
+	execCfg, err := ParseExecArgs(os.Args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	app, err := NewApp(execCfg.SecretConfig)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+
+	code, err := RunExec(context.Background(), app, execCfg)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	os.Exit(code)
+}
@@ -1,13 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockSecretsManagerClient is a mock implementation of SecretsManagerClient
@@ -38,8 +42,9 @@ func TestParseArgs(t *testing.T) {
 			args:    []string{"aws-ssm", "my-secret"},
 			envVars: map[string]string{"AWS_REGION": "us-east-1"},
 			expected: Config{
-				SecretID: "my-secret",
-				Region:   "us-east-1",
+				SecretID:       "my-secret",
+				Region:         "us-east-1",
+				MaxConcurrency: 5,
 			},
 			wantErr: false,
 		},
@@ -47,18 +52,20 @@ func TestParseArgs(t *testing.T) {
 			name: "secret ID with region from args",
 			args: []string{"aws-ssm", "my-secret", "eu-west-1"},
 			expected: Config{
-				SecretID: "my-secret",
-				Region:   "eu-west-1",
+				SecretID:       "my-secret",
+				Region:         "eu-west-1",
+				MaxConcurrency: 5,
 			},
 			wantErr: false,
 		},
 		{
-			name: "region from args overrides env",
-			args: []string{"aws-ssm", "my-secret", "eu-west-1"},
+			name:    "region from args overrides env",
+			args:    []string{"aws-ssm", "my-secret", "eu-west-1"},
 			envVars: map[string]string{"AWS_REGION": "us-east-1"},
 			expected: Config{
-				SecretID: "my-secret",
-				Region:   "eu-west-1",
+				SecretID:       "my-secret",
+				Region:         "eu-west-1",
+				MaxConcurrency: 5,
 			},
 			wantErr: false,
 		},
@@ -71,8 +78,9 @@ func TestParseArgs(t *testing.T) {
 			name: "ARN as secret ID",
 			args: []string{"aws-ssm", "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret-AbCdEf", "us-east-1"},
 			expected: Config{
-				SecretID: "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret-AbCdEf",
-				Region:   "us-east-1",
+				SecretID:       "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret-AbCdEf",
+				Region:         "us-east-1",
+				MaxConcurrency: 5,
 			},
 			wantErr: false,
 		},
@@ -103,6 +111,119 @@ func TestParseArgs(t *testing.T) {
 	}
 }
 
+func TestParseArgs_VersionSelectorsAndDiff(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "version-id flag",
+			args: []string{"aws-ssm", "my-secret", "--version-id", "v1"},
+			want: Config{SecretID: "my-secret", Region: "us-east-1", VersionID: "v1", MaxConcurrency: 5},
+		},
+		{
+			name: "version-stage flag",
+			args: []string{"aws-ssm", "my-secret", "--version-stage", "AWSPREVIOUS"},
+			want: Config{SecretID: "my-secret", Region: "us-east-1", VersionStage: "AWSPREVIOUS", MaxConcurrency: 5},
+		},
+		{
+			name: "diff flag",
+			args: []string{"aws-ssm", "my-secret", "--diff"},
+			want: Config{SecretID: "my-secret", Region: "us-east-1", Diff: true, MaxConcurrency: 5},
+		},
+		{
+			name:    "version-id and version-stage are mutually exclusive",
+			args:    []string{"aws-ssm", "my-secret", "--version-id", "v1", "--version-stage", "AWSPREVIOUS"},
+			wantErr: true,
+		},
+		{
+			name:    "version-id requires a value",
+			args:    []string{"aws-ssm", "my-secret", "--version-id"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArgs(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseArgs_KeyFormatMask(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "key flag",
+			args: []string{"aws-ssm", "my-secret", "--key", "password"},
+			want: Config{SecretID: "my-secret", Region: "us-east-1", Key: "password", MaxConcurrency: 5},
+		},
+		{
+			name: "format flag",
+			args: []string{"aws-ssm", "my-secret", "--format", "env"},
+			want: Config{SecretID: "my-secret", Region: "us-east-1", Format: "env", MaxConcurrency: 5},
+		},
+		{
+			name: "mask flag",
+			args: []string{"aws-ssm", "my-secret", "--mask"},
+			want: Config{SecretID: "my-secret", Region: "us-east-1", Mask: true, MaxConcurrency: 5},
+		},
+		{
+			name: "template format with template body",
+			args: []string{"aws-ssm", "my-secret", "--format", "template", "--template", "{{.username}}"},
+			want: Config{SecretID: "my-secret", Region: "us-east-1", Format: "template", Template: "{{.username}}", MaxConcurrency: 5},
+		},
+		{
+			name:    "unsupported format",
+			args:    []string{"aws-ssm", "my-secret", "--format", "xml"},
+			wantErr: true,
+		},
+		{
+			name:    "template format without --template",
+			args:    []string{"aws-ssm", "my-secret", "--format", "template"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArgs(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMaskingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := &maskingWriter{w: &buf, secret: "secret123"}
+
+	n, err := w.Write([]byte("error talking to AWS: got secret123 back\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, len("error talking to AWS: got secret123 back\n"), n)
+	assert.Equal(t, "error talking to AWS: got ***MASKED*** back\n", buf.String())
+}
+
 func TestFormatOutput(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -195,7 +316,7 @@ func TestApp_GetSecret(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockClient := new(MockSecretsManagerClient)
-			
+
 			if tt.mockError != nil {
 				mockClient.On("GetSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.GetSecretValueInput) bool {
 					return *input.SecretId == tt.secretID
@@ -228,6 +349,89 @@ func TestApp_GetSecret(t *testing.T) {
 	}
 }
 
+func TestApp_GetSecret_VersionSelectors(t *testing.T) {
+	tests := []struct {
+		name         string
+		versionID    string
+		versionStage string
+		matchInput   func(input *secretsmanager.GetSecretValueInput) bool
+	}{
+		{
+			name: "no selector leaves VersionId/VersionStage unset",
+			matchInput: func(input *secretsmanager.GetSecretValueInput) bool {
+				return input.VersionId == nil && input.VersionStage == nil
+			},
+		},
+		{
+			name:      "explicit version ID",
+			versionID: "v1-id",
+			matchInput: func(input *secretsmanager.GetSecretValueInput) bool {
+				return input.VersionId != nil && *input.VersionId == "v1-id" && input.VersionStage == nil
+			},
+		},
+		{
+			name:         "explicit version stage",
+			versionStage: "AWSPREVIOUS",
+			matchInput: func(input *secretsmanager.GetSecretValueInput) bool {
+				return input.VersionId == nil && input.VersionStage != nil && *input.VersionStage == "AWSPREVIOUS"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(MockSecretsManagerClient)
+			mockClient.On("GetSecretValue", mock.Anything, mock.MatchedBy(tt.matchInput)).
+				Return(&secretsmanager.GetSecretValueOutput{SecretString: stringPtr("secret-value")}, nil)
+
+			app := &App{
+				Client: mockClient,
+				Config: Config{
+					SecretID:     "my-secret",
+					Region:       "us-east-1",
+					VersionID:    tt.versionID,
+					VersionStage: tt.versionStage,
+				},
+			}
+
+			got, err := app.GetSecret(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "secret-value", got)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestApp_GetSecretDiff(t *testing.T) {
+	mockClient := new(MockSecretsManagerClient)
+	mockClient.On("GetSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.GetSecretValueInput) bool {
+		return input.VersionStage != nil && *input.VersionStage == "AWSPREVIOUS"
+	})).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: stringPtr(`{"password":"old","username":"admin"}`),
+	}, nil)
+	mockClient.On("GetSecretValue", mock.Anything, mock.MatchedBy(func(input *secretsmanager.GetSecretValueInput) bool {
+		return input.VersionStage != nil && *input.VersionStage == "AWSCURRENT"
+	})).Return(&secretsmanager.GetSecretValueOutput{
+		SecretString: stringPtr(`{"password":"new","username":"admin"}`),
+	}, nil)
+
+	app := &App{
+		Client: mockClient,
+		Config: Config{
+			SecretID: "my-secret",
+			Region:   "us-east-1",
+		},
+	}
+
+	got, err := app.GetSecretDiff(context.Background())
+	assert.NoError(t, err)
+	assert.Contains(t, got, "--- AWSPREVIOUS")
+	assert.Contains(t, got, "+++ AWSCURRENT")
+	assert.Contains(t, got, `-   "password": "old"`)
+	assert.Contains(t, got, `+   "password": "new"`)
+	mockClient.AssertExpectations(t)
+}
+
 func TestApp_GetSecret_Integration(t *testing.T) {
 	// Skip integration tests in short mode
 	if testing.Short() {
@@ -245,7 +449,199 @@ func TestApp_GetSecret_Integration(t *testing.T) {
 	t.Skip("Integration test requires AWS credentials and test secret")
 }
 
+func TestParseArgs_Batch(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	t.Run("multiple positional secret IDs", func(t *testing.T) {
+		got, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"secret-a", "secret-b", "secret-c"}, got.SecretIDs)
+		assert.Empty(t, got.SecretID)
+		assert.Equal(t, "us-east-1", got.Region)
+		assert.Equal(t, 5, got.MaxConcurrency)
+	})
+
+	t.Run("at file argument", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ids.txt")
+		require.NoError(t, os.WriteFile(path, []byte("secret-a\n\n# a comment\nsecret-b\n"), 0o644))
+
+		got, err := ParseArgs([]string{"aws-ssm", "@" + path})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"secret-a", "secret-b"}, got.SecretIDs)
+	})
+
+	t.Run("at file argument that does not exist", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "@/nonexistent/ids.txt"})
+		assert.Error(t, err)
+	})
+
+	t.Run("at file with no AWS_REGION set errors", func(t *testing.T) {
+		os.Unsetenv("AWS_REGION")
+		defer os.Setenv("AWS_REGION", "us-east-1")
+
+		path := filepath.Join(t.TempDir(), "ids.txt")
+		require.NoError(t, os.WriteFile(path, []byte("secret-a\n"), 0o644))
+
+		_, err := ParseArgs([]string{"aws-ssm", "@" + path})
+		assert.Error(t, err)
+	})
+
+	t.Run("max-concurrency flag", func(t *testing.T) {
+		got, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c", "--max-concurrency", "2"})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, got.MaxConcurrency)
+	})
+
+	t.Run("max-concurrency requires a positive integer", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "my-secret", "--max-concurrency", "0"})
+		assert.Error(t, err)
+	})
+
+	t.Run("format cannot be combined with batch retrieval", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c", "--format", "json"})
+		assert.Error(t, err)
+	})
+
+	t.Run("key cannot be combined with batch retrieval", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c", "--key", "password"})
+		assert.Error(t, err)
+	})
+
+	t.Run("diff cannot be combined with batch retrieval", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c", "--diff"})
+		assert.Error(t, err)
+	})
+
+	t.Run("mask cannot be combined with batch retrieval", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c", "--mask"})
+		assert.Error(t, err)
+	})
+
+	t.Run("version-id cannot be combined with batch retrieval", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c", "--version-id", "abc123"})
+		assert.Error(t, err)
+	})
+
+	t.Run("version-stage cannot be combined with batch retrieval", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c", "--version-stage", "AWSPREVIOUS"})
+		assert.Error(t, err)
+	})
+
+	t.Run("explicit raw format is still allowed with batch retrieval", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "secret-a", "secret-b", "secret-c", "--format", "raw"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("at file batch rejects --key", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ids.txt")
+		require.NoError(t, os.WriteFile(path, []byte("secret-a\nsecret-b\n"), 0o644))
+
+		_, err := ParseArgs([]string{"aws-ssm", "@" + path, "--key", "password"})
+		assert.Error(t, err)
+	})
+}
+
+func TestParseArgs_CacheFile(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	t.Run("cache-file and cache-key flags", func(t *testing.T) {
+		got, err := ParseArgs([]string{"aws-ssm", "my-secret", "--cache-file", "/tmp/cache.json", "--cache-key", "k", "--cache-ttl", "5m"})
+		assert.NoError(t, err)
+		assert.Equal(t, "/tmp/cache.json", got.CacheFile)
+		assert.Equal(t, "k", got.CacheKey)
+		assert.Equal(t, 5*time.Minute, got.CacheTTL)
+	})
+
+	t.Run("cache-file requires a value", func(t *testing.T) {
+		_, err := ParseArgs([]string{"aws-ssm", "my-secret", "--cache-file"})
+		assert.Error(t, err)
+	})
+}
+
+func TestNewFileCachingSecretsManagerClient(t *testing.T) {
+	t.Run("round-trips through the cache file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cache.json")
+		mockClient := new(MockSecretsManagerClient)
+		mockClient.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{
+			SecretId: stringPtr("my-secret"),
+		}).Return(&secretsmanager.GetSecretValueOutput{SecretString: stringPtr("value")}, nil).Once()
+
+		cfg := Config{Region: "us-east-1", CacheFile: path, CacheKey: "test-key", CacheTTL: time.Minute}
+		client, err := newFileCachingSecretsManagerClient(mockClient, cfg)
+		require.NoError(t, err)
+
+		input := &secretsmanager.GetSecretValueInput{SecretId: stringPtr("my-secret")}
+
+		first, err := client.GetSecretValue(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "value", *first.SecretString)
+
+		second, err := client.GetSecretValue(context.Background(), input)
+		require.NoError(t, err)
+		assert.Equal(t, "value", *second.SecretString)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("requires AWS_ACCESS_KEY_ID when --cache-key is not set", func(t *testing.T) {
+		os.Unsetenv("AWS_ACCESS_KEY_ID")
+
+		cfg := Config{Region: "us-east-1", CacheFile: filepath.Join(t.TempDir(), "cache.json")}
+		_, err := newFileCachingSecretsManagerClient(new(MockSecretsManagerClient), cfg)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewApp_AWSSMSchemeReusesCachedClient(t *testing.T) {
+	cfg := Config{
+		Region:   "us-east-1",
+		Scheme:   "aws-sm",
+		Ref:      "my-secret",
+		CacheTTL: time.Minute,
+	}
+
+	app, err := NewApp(cfg)
+	require.NoError(t, err)
+
+	provider, ok := app.Provider.(*awsSecretsManagerProvider)
+	require.True(t, ok, "app.Provider should be *awsSecretsManagerProvider")
+	assert.Same(t, app.Client, provider.client, "aws-sm:// should reuse the same (possibly cached) client as the bare-ID path")
+}
+
+func TestApp_GetSecrets(t *testing.T) {
+	mockClient := new(MockSecretsManagerClient)
+	mockClient.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{
+		SecretId: stringPtr("secret-a"),
+	}).Return(&secretsmanager.GetSecretValueOutput{SecretString: stringPtr("value-a")}, nil)
+	mockClient.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{
+		SecretId: stringPtr("secret-b"),
+	}).Return(&secretsmanager.GetSecretValueOutput{SecretString: stringPtr("value-b")}, nil)
+	mockClient.On("GetSecretValue", mock.Anything, &secretsmanager.GetSecretValueInput{
+		SecretId: stringPtr("secret-c"),
+	}).Return((*secretsmanager.GetSecretValueOutput)(nil), assert.AnError)
+
+	app := &App{
+		Client: mockClient,
+		Config: Config{Region: "us-east-1"},
+	}
+
+	values, errs := app.GetSecrets(context.Background(), []string{"secret-a", "secret-b", "secret-c"}, 2)
+
+	assert.Equal(t, map[string]string{"secret-a": "value-a", "secret-b": "value-b"}, values)
+	assert.Len(t, errs, 1)
+	assert.Error(t, errs["secret-c"])
+	mockClient.AssertExpectations(t)
+}
+
+func TestApp_GetSecrets_Empty(t *testing.T) {
+	app := &App{Config: Config{Region: "us-east-1"}}
+
+	values, errs := app.GetSecrets(context.Background(), nil, 5)
+	assert.Empty(t, values)
+	assert.Empty(t, errs)
+}
+
 // Helper function to create string pointers
 func stringPtr(s string) *string {
 	return &s
-}
\ No newline at end of file
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Secret is the value returned by a Provider.
+type Secret struct {
+	Value string
+}
+
+// Provider is implemented by each secret backend (AWS Secrets Manager, AWS
+// SSM Parameter Store, HashiCorp Vault, Azure Key Vault, GCP Secret Manager,
+// ...). ref is the part of the secret reference after the "scheme://"
+// prefix, e.g. for "vault://secret/myapp#password" ref is
+// "secret/myapp#password".
+type Provider interface {
+	GetSecret(ctx context.Context, ref string) (Secret, error)
+}
+
+// ProviderFactory builds a Provider for the given AWS region. Providers that
+// don't need a region (Vault, Azure Key Vault, GCP Secret Manager) may
+// ignore it.
+type ProviderFactory func(region string) (Provider, error)
+
+// providerRegistry maps a URI scheme (e.g. "vault") to the factory that
+// builds its Provider.
+var providerRegistry = map[string]ProviderFactory{}
+
+// RegisterProvider registers a ProviderFactory under the given URI scheme.
+// It is meant to be called from package init() functions.
+func RegisterProvider(scheme string, factory ProviderFactory) {
+	providerRegistry[scheme] = factory
+}
+
+// lookupProvider returns the ProviderFactory registered for scheme, or an
+// error listing the supported schemes.
+func lookupProvider(scheme string) (ProviderFactory, error) {
+	factory, ok := providerRegistry[scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported secret provider scheme %q", scheme)
+	}
+	return factory, nil
+}
+
+// ParseRef splits a secret reference of the form "scheme://rest" into its
+// scheme and remainder. Bare IDs without a "scheme://" prefix (the original
+// aws-ssm-light behavior: a raw AWS Secrets Manager ID or ARN) return
+// ok=false so callers fall back to the default AWS Secrets Manager path.
+func ParseRef(ref string) (scheme, rest string, ok bool) {
+	i := strings.Index(ref, "://")
+	if i < 0 {
+		return "", ref, false
+	}
+	return ref[:i], ref[i+len("://"):], true
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockSSMGetParameterAPI is a mock implementation of SSMGetParameterAPI.
+type MockSSMGetParameterAPI struct {
+	mock.Mock
+}
+
+func (m *MockSSMGetParameterAPI) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*ssm.GetParameterOutput), args.Error(1)
+}
+
+// httpsRedirectClient returns an *http.Client whose requests (regardless of
+// host or scheme) are transparently redirected to server. azureKeyVaultProvider
+// and gcpSecretManagerProvider build their own hardcoded "https://..." URLs,
+// so tests route them to an httptest.Server by dialing its listener directly
+// instead of performing a real TLS handshake to the real host.
+func httpsRedirectClient(server *httptest.Server) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				return net.Dial(network, server.Listener.Addr().String())
+			},
+		},
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	tests := []struct {
+		name       string
+		ref        string
+		wantScheme string
+		wantRest   string
+		wantOK     bool
+	}{
+		{
+			name:   "bare secret ID",
+			ref:    "my-secret",
+			wantOK: false,
+		},
+		{
+			name:   "ARN",
+			ref:    "arn:aws:secretsmanager:us-east-1:123456789012:secret:my-secret-AbCdEf",
+			wantOK: false,
+		},
+		{
+			name:       "aws-sm scheme",
+			ref:        "aws-sm://my-secret",
+			wantScheme: "aws-sm",
+			wantRest:   "my-secret",
+			wantOK:     true,
+		},
+		{
+			name:       "aws-ssm scheme",
+			ref:        "aws-ssm://app/prod/db-password",
+			wantScheme: "aws-ssm",
+			wantRest:   "app/prod/db-password",
+			wantOK:     true,
+		},
+		{
+			name:       "vault scheme with key fragment",
+			ref:        "vault://secret/myapp#password",
+			wantScheme: "vault",
+			wantRest:   "secret/myapp#password",
+			wantOK:     true,
+		},
+		{
+			name:       "azkv scheme",
+			ref:        "azkv://myvault/db-password",
+			wantScheme: "azkv",
+			wantRest:   "myvault/db-password",
+			wantOK:     true,
+		},
+		{
+			name:       "gcpsm scheme",
+			ref:        "gcpsm://my-project/db-password",
+			wantScheme: "gcpsm",
+			wantRest:   "my-project/db-password",
+			wantOK:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme, rest, ok := ParseRef(tt.ref)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseRef(%q) ok = %v, want %v", tt.ref, ok, tt.wantOK)
+			}
+			if !tt.wantOK {
+				return
+			}
+			if scheme != tt.wantScheme || rest != tt.wantRest {
+				t.Fatalf("ParseRef(%q) = (%q, %q), want (%q, %q)", tt.ref, scheme, rest, tt.wantScheme, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestLookupProvider(t *testing.T) {
+	for _, scheme := range []string{"aws-sm", "aws-ssm", "vault", "azkv", "gcpsm"} {
+		if _, err := lookupProvider(scheme); err != nil {
+			t.Errorf("lookupProvider(%q) returned unexpected error: %v", scheme, err)
+		}
+	}
+
+	if _, err := lookupProvider("unknown"); err == nil {
+		t.Error("lookupProvider(\"unknown\") expected an error, got nil")
+	}
+}
+
+func TestParseArgs_ProviderScheme(t *testing.T) {
+	t.Setenv("AWS_REGION", "us-east-1")
+
+	got, err := ParseArgs([]string{"aws-ssm", "vault://secret/myapp#password"})
+	if err != nil {
+		t.Fatalf("ParseArgs returned unexpected error: %v", err)
+	}
+	if got.Scheme != "vault" || got.Ref != "secret/myapp#password" {
+		t.Fatalf("ParseArgs() = %+v, want Scheme=vault Ref=secret/myapp#password", got)
+	}
+}
+
+func TestAWSParameterStoreProvider_GetSecret(t *testing.T) {
+	mockClient := new(MockSSMGetParameterAPI)
+	mockClient.On("GetParameter", mock.Anything, &ssm.GetParameterInput{
+		Name:           aws.String("app/prod/db-password"),
+		WithDecryption: boolPtr(true),
+	}).Return(&ssm.GetParameterOutput{
+		Parameter: &ssmtypes.Parameter{Value: aws.String("hunter2")},
+	}, nil)
+
+	p := &awsParameterStoreProvider{client: mockClient}
+	got, err := p.GetSecret(context.Background(), "app/prod/db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", got.Value)
+	mockClient.AssertExpectations(t)
+}
+
+func TestAWSParameterStoreProvider_GetSecret_NoValue(t *testing.T) {
+	mockClient := new(MockSSMGetParameterAPI)
+	mockClient.On("GetParameter", mock.Anything, mock.Anything).Return(&ssm.GetParameterOutput{}, nil)
+
+	p := &awsParameterStoreProvider{client: mockClient}
+	_, err := p.GetSecret(context.Background(), "app/prod/db-password")
+	assert.Error(t, err)
+}
+
+func TestAWSParameterStoreProvider_GetSecret_Error(t *testing.T) {
+	mockClient := new(MockSSMGetParameterAPI)
+	mockClient.On("GetParameter", mock.Anything, mock.Anything).Return((*ssm.GetParameterOutput)(nil), fmt.Errorf("access denied"))
+
+	p := &awsParameterStoreProvider{client: mockClient}
+	_, err := p.GetSecret(context.Background(), "app/prod/db-password")
+	assert.Error(t, err)
+}
+
+func TestVaultProvider_GetSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/secret/data/myapp", r.URL.Path)
+		assert.Equal(t, "test-token", r.Header.Get("X-Vault-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data":{"data":{"password":"s3cr3t"}}}`)
+	}))
+	defer server.Close()
+
+	p := &vaultProvider{addr: server.URL, token: "test-token", doer: server.Client()}
+	got, err := p.GetSecret(context.Background(), "secret/myapp#password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", got.Value)
+}
+
+func TestVaultProvider_GetSecret_InvalidRef(t *testing.T) {
+	p := &vaultProvider{addr: "http://127.0.0.1", token: "t", doer: http.DefaultClient}
+	_, err := p.GetSecret(context.Background(), "no-hash-key")
+	assert.Error(t, err)
+}
+
+func TestVaultProvider_GetSecret_MissingKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"data":{"username":"admin"}}}`)
+	}))
+	defer server.Close()
+
+	p := &vaultProvider{addr: server.URL, token: "test-token", doer: server.Client()}
+	_, err := p.GetSecret(context.Background(), "secret/myapp#password")
+	assert.Error(t, err)
+}
+
+func TestVaultProvider_GetSecret_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := &vaultProvider{addr: server.URL, token: "test-token", doer: server.Client()}
+	_, err := p.GetSecret(context.Background(), "secret/myapp#password")
+	assert.Error(t, err)
+}
+
+func TestAzureKeyVaultProvider_GetSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/secrets/db-password", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"value":"s3cr3t"}`)
+	}))
+	defer server.Close()
+
+	p := &azureKeyVaultProvider{token: "test-token", doer: httpsRedirectClient(server)}
+	got, err := p.GetSecret(context.Background(), "myvault/db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", got.Value)
+}
+
+func TestAzureKeyVaultProvider_GetSecret_InvalidRef(t *testing.T) {
+	p := &azureKeyVaultProvider{token: "t", doer: http.DefaultClient}
+	_, err := p.GetSecret(context.Background(), "no-slash")
+	assert.Error(t, err)
+}
+
+func TestAzureKeyVaultProvider_GetSecret_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	p := &azureKeyVaultProvider{token: "test-token", doer: httpsRedirectClient(server)}
+	_, err := p.GetSecret(context.Background(), "myvault/db-password")
+	assert.Error(t, err)
+}
+
+func TestGCPSecretManagerProvider_GetSecret(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte("s3cr3t"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/projects/my-project/secrets/db-password/versions/latest:access", r.URL.Path)
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"payload":{"data":%q}}`, payload)
+	}))
+	defer server.Close()
+
+	p := &gcpSecretManagerProvider{token: "test-token", doer: httpsRedirectClient(server)}
+	got, err := p.GetSecret(context.Background(), "my-project/db-password")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", got.Value)
+}
+
+func TestGCPSecretManagerProvider_GetSecret_InvalidRef(t *testing.T) {
+	p := &gcpSecretManagerProvider{token: "t", doer: http.DefaultClient}
+	_, err := p.GetSecret(context.Background(), "no-slash")
+	assert.Error(t, err)
+}
+
+func TestGCPSecretManagerProvider_GetSecret_BadPayload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"payload":{"data":"not-valid-base64!!"}}`)
+	}))
+	defer server.Close()
+
+	p := &gcpSecretManagerProvider{token: "test-token", doer: httpsRedirectClient(server)}
+	_, err := p.GetSecret(context.Background(), "my-project/db-password")
+	assert.Error(t, err)
+}
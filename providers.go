@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+)
+
+func init() {
+	RegisterProvider("aws-sm", newAWSSecretsManagerProvider)
+	RegisterProvider("aws-ssm", newAWSParameterStoreProvider)
+	RegisterProvider("vault", newVaultProvider)
+	RegisterProvider("azkv", newAzureKeyVaultProvider)
+	RegisterProvider("gcpsm", newGCPSecretManagerProvider)
+}
+
+// -- aws-sm://name -----------------------------------------------------
+
+// awsSecretsManagerProvider fetches secrets from AWS Secrets Manager. It is
+// the scheme form of the legacy bare-ID behavior handled directly in
+// App.GetSecret.
+type awsSecretsManagerProvider struct {
+	client SecretsManagerClient
+}
+
+func newAWSSecretsManagerProvider(region string) (Provider, error) {
+	awsCfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &awsSecretsManagerProvider{client: secretsmanager.NewFromConfig(awsCfg)}, nil
+}
+
+func (p *awsSecretsManagerProvider) GetSecret(ctx context.Context, ref string) (Secret, error) {
+	result, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &ref})
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to get secret value: %w", err)
+	}
+	if result.SecretString == nil {
+		return Secret{}, fmt.Errorf("secret does not contain a string value")
+	}
+	return Secret{Value: *result.SecretString}, nil
+}
+
+// -- aws-ssm://path ------------------------------------------------------
+
+// SSMGetParameterAPI is the subset of *ssm.Client that
+// awsParameterStoreProvider needs, so tests can supply a stub instead of a
+// real AWS client (mirrors SecretsManagerClient above).
+type SSMGetParameterAPI interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// awsParameterStoreProvider fetches secrets from AWS Systems Manager
+// Parameter Store, decrypting SecureString parameters.
+type awsParameterStoreProvider struct {
+	client SSMGetParameterAPI
+}
+
+func newAWSParameterStoreProvider(region string) (Provider, error) {
+	awsCfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &awsParameterStoreProvider{client: ssm.NewFromConfig(awsCfg)}, nil
+}
+
+func (p *awsParameterStoreProvider) GetSecret(ctx context.Context, ref string) (Secret, error) {
+	result, err := p.client.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           &ref,
+		WithDecryption: boolPtr(true),
+	})
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to get parameter value: %w", err)
+	}
+	if result.Parameter == nil || result.Parameter.Value == nil {
+		return Secret{}, fmt.Errorf("parameter does not contain a value")
+	}
+	return Secret{Value: *result.Parameter.Value}, nil
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+// -- vault://mount/path#key ----------------------------------------------
+
+// vaultProvider fetches secrets from HashiCorp Vault's KV v2 engine. It
+// talks to the address in VAULT_ADDR using the token in VAULT_TOKEN, both of
+// which are the same environment variables the official Vault CLI uses.
+type vaultProvider struct {
+	addr  string
+	token string
+	doer  *http.Client
+}
+
+func newVaultProvider(_ string) (Provider, error) {
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		return nil, fmt.Errorf("VAULT_ADDR environment variable must be set for vault:// references")
+	}
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("VAULT_TOKEN environment variable must be set for vault:// references")
+	}
+	return &vaultProvider{addr: strings.TrimRight(addr, "/"), token: token, doer: http.DefaultClient}, nil
+}
+
+// GetSecret expects ref in the form "mount/path#key", e.g.
+// "secret/myapp#password".
+func (p *vaultProvider) GetSecret(ctx context.Context, ref string) (Secret, error) {
+	mountPath, key, ok := strings.Cut(ref, "#")
+	if !ok || key == "" {
+		return Secret{}, fmt.Errorf("vault reference %q must be in the form mount/path#key", ref)
+	}
+	mount, path, ok := strings.Cut(mountPath, "/")
+	if !ok {
+		return Secret{}, fmt.Errorf("vault reference %q must be in the form mount/path#key", ref)
+	}
+
+	url := fmt.Sprintf("%s/v1/%s/data/%s", p.addr, mount, path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.doer.Do(req)
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Secret{}, fmt.Errorf("vault returned status %s for %s", resp.Status, url)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Secret{}, fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	value, ok := body.Data.Data[key]
+	if !ok {
+		return Secret{}, fmt.Errorf("key %q not found at %s", key, mountPath)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return Secret{}, fmt.Errorf("key %q at %s is not a string value", key, mountPath)
+	}
+	return Secret{Value: str}, nil
+}
+
+// -- azkv://vault/name -----------------------------------------------------
+
+// azureKeyVaultProvider fetches secrets from Azure Key Vault. Authentication
+// is a bearer token supplied via AZURE_KEYVAULT_TOKEN (e.g. the output of
+// `az account get-access-token --resource https://vault.azure.net`); full
+// AAD auth flows are out of scope for this lightweight tool.
+type azureKeyVaultProvider struct {
+	token string
+	doer  *http.Client
+}
+
+func newAzureKeyVaultProvider(_ string) (Provider, error) {
+	token := os.Getenv("AZURE_KEYVAULT_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("AZURE_KEYVAULT_TOKEN environment variable must be set for azkv:// references")
+	}
+	return &azureKeyVaultProvider{token: token, doer: http.DefaultClient}, nil
+}
+
+// GetSecret expects ref in the form "vault/name", e.g. "myvault/db-password".
+func (p *azureKeyVaultProvider) GetSecret(ctx context.Context, ref string) (Secret, error) {
+	vault, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		return Secret{}, fmt.Errorf("azure key vault reference %q must be in the form vault/name", ref)
+	}
+
+	url := fmt.Sprintf("https://%s.vault.azure.net/secrets/%s?api-version=7.4", vault, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to build azure key vault request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.doer.Do(req)
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to reach azure key vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Secret{}, fmt.Errorf("azure key vault returned status %s for %s", resp.Status, url)
+	}
+
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Secret{}, fmt.Errorf("failed to decode azure key vault response: %w", err)
+	}
+	return Secret{Value: body.Value}, nil
+}
+
+// -- gcpsm://project/name --------------------------------------------------
+
+// gcpSecretManagerProvider fetches secrets from GCP Secret Manager, always
+// the "latest" version. Authentication is a bearer token supplied via
+// GOOGLE_OAUTH_ACCESS_TOKEN (e.g. the output of `gcloud auth print-access-token`).
+type gcpSecretManagerProvider struct {
+	token string
+	doer  *http.Client
+}
+
+func newGCPSecretManagerProvider(_ string) (Provider, error) {
+	token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GOOGLE_OAUTH_ACCESS_TOKEN environment variable must be set for gcpsm:// references")
+	}
+	return &gcpSecretManagerProvider{token: token, doer: http.DefaultClient}, nil
+}
+
+// GetSecret expects ref in the form "project/name", e.g. "my-project/db-password".
+func (p *gcpSecretManagerProvider) GetSecret(ctx context.Context, ref string) (Secret, error) {
+	project, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		return Secret{}, fmt.Errorf("gcp secret manager reference %q must be in the form project/name", ref)
+	}
+
+	url := fmt.Sprintf("https://secretmanager.googleapis.com/v1/projects/%s/secrets/%s/versions/latest:access", project, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to build gcp secret manager request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.doer.Do(req)
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to reach gcp secret manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Secret{}, fmt.Errorf("gcp secret manager returned status %s for %s", resp.Status, url)
+	}
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Secret{}, fmt.Errorf("failed to decode gcp secret manager response: %w", err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return Secret{}, fmt.Errorf("failed to decode secret payload: %w", err)
+	}
+	return Secret{Value: string(decoded)}, nil
+}
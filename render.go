@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// supportedFormats lists the valid --format values.
+var supportedFormats = map[string]bool{
+	"":         true, // alias for "raw"
+	"raw":      true,
+	"json":     true,
+	"env":      true,
+	"dotenv":   true,
+	"yaml":     true,
+	"template": true,
+}
+
+// ExtractKey plucks a single field out of a JSON secret using a dotted path
+// (e.g. "database.password"), mirroring `jq -r .database.password`. String
+// values are returned unquoted; anything else is JSON-encoded.
+func ExtractKey(secretValue, keyPath string) (string, error) {
+	var data interface{}
+	if err := json.Unmarshal([]byte(secretValue), &data); err != nil {
+		return "", fmt.Errorf("--key requires a JSON secret: %w", err)
+	}
+
+	path := strings.TrimPrefix(keyPath, ".")
+	for _, part := range strings.Split(path, ".") {
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("key %q not found: %q is not an object", keyPath, part)
+		}
+		value, ok := obj[part]
+		if !ok {
+			return "", fmt.Errorf("key %q not found in secret", keyPath)
+		}
+		data = value
+	}
+
+	return stringifyJSON(data), nil
+}
+
+// stringifyJSON renders a decoded JSON value as a plain string: strings
+// pass through unquoted, everything else is JSON-encoded.
+func stringifyJSON(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(out)
+}
+
+// RenderOutput renders secretValue in the requested --format. An empty
+// format (or "raw") preserves the original aws-ssm-light behavior of
+// FormatOutput: print the value as-is.
+func RenderOutput(secretValue, format, templateStr string) (string, error) {
+	switch format {
+	case "", "raw":
+		return FormatOutput(secretValue), nil
+	case "json":
+		return renderJSON(secretValue)
+	case "env":
+		return renderEnv(secretValue, true)
+	case "dotenv":
+		return renderEnv(secretValue, false)
+	case "yaml":
+		return renderYAML(secretValue)
+	case "template":
+		return renderTemplate(secretValue, templateStr)
+	default:
+		return "", fmt.Errorf("unsupported --format %q", format)
+	}
+}
+
+func renderJSON(secretValue string) (string, error) {
+	data := decodeJSONOrString(secretValue)
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to render JSON: %w", err)
+	}
+	return string(out) + "\n", nil
+}
+
+func renderYAML(secretValue string) (string, error) {
+	data := decodeJSONOrString(secretValue)
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render YAML: %w", err)
+	}
+	return string(out), nil
+}
+
+func renderTemplate(secretValue, templateStr string) (string, error) {
+	if templateStr == "" {
+		return "", fmt.Errorf("--format template requires --template")
+	}
+	data := decodeJSONOrString(secretValue)
+
+	tmpl, err := template.New("aws-ssm").Parse(templateStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse --template: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to execute --template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// renderEnv emits secretValue as shell "export KEY=value" lines (export=true)
+// or ".env" style "KEY=value" lines (export=false). JSON object secrets emit
+// one line per field (keys upper-cased, sorted for stable output); anything
+// else is emitted as a single SECRET_VALUE field.
+func renderEnv(secretValue string, export bool) (string, error) {
+	fields := toEnvFields(secretValue)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		if export {
+			fmt.Fprintf(&sb, "export %s=%s\n", k, shellQuote(fields[k]))
+		} else {
+			fmt.Fprintf(&sb, "%s=%s\n", k, dotenvQuote(fields[k]))
+		}
+	}
+	return sb.String(), nil
+}
+
+func toEnvFields(secretValue string) map[string]string {
+	var data interface{}
+	if err := json.Unmarshal([]byte(secretValue), &data); err != nil {
+		return map[string]string{"SECRET_VALUE": secretValue}
+	}
+	obj, ok := data.(map[string]interface{})
+	if !ok {
+		return map[string]string{"SECRET_VALUE": stringifyJSON(data)}
+	}
+	fields := make(map[string]string, len(obj))
+	for k, v := range obj {
+		fields[strings.ToUpper(k)] = stringifyJSON(v)
+	}
+	return fields
+}
+
+func decodeJSONOrString(secretValue string) interface{} {
+	var data interface{}
+	if err := json.Unmarshal([]byte(secretValue), &data); err != nil {
+		return secretValue
+	}
+	return data
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// dotenvQuote returns s unchanged unless it contains a carriage return or
+// line feed, in which case it double-quotes it and escapes backslashes,
+// double quotes, and line breaks. Plain values are left bare so output stays
+// compatible with dotenv consumers that treat quote characters as literal
+// value content (e.g. "docker run --env-file"); only a multi-line field
+// (a PEM certificate or private key, say) needs escaping to avoid being
+// written across multiple raw lines, which no line-oriented ".env" parser
+// can read back correctly.
+func dotenvQuote(s string) string {
+	if !strings.ContainsAny(s, "\r\n") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\r", `\r`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractKey(t *testing.T) {
+	tests := []struct {
+		name        string
+		secretValue string
+		keyPath     string
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "top-level field",
+			secretValue: `{"username":"admin","password":"secret123"}`,
+			keyPath:     "password",
+			want:        "secret123",
+		},
+		{
+			name:        "leading dot is ignored",
+			secretValue: `{"username":"admin","password":"secret123"}`,
+			keyPath:     ".password",
+			want:        "secret123",
+		},
+		{
+			name:        "nested field",
+			secretValue: `{"database":{"password":"nested-secret"}}`,
+			keyPath:     "database.password",
+			want:        "nested-secret",
+		},
+		{
+			name:        "non-string value is JSON-encoded",
+			secretValue: `{"port":5432}`,
+			keyPath:     "port",
+			want:        "5432",
+		},
+		{
+			name:        "missing key",
+			secretValue: `{"username":"admin"}`,
+			keyPath:     "password",
+			wantErr:     true,
+		},
+		{
+			name:        "not JSON",
+			secretValue: "plain-text-password",
+			keyPath:     "password",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractKey(tt.secretValue, tt.keyPath)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ExtractKey() expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExtractKey() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("ExtractKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderOutput(t *testing.T) {
+	const secret = `{"username":"admin","password":"secret123"}`
+
+	tests := []struct {
+		name     string
+		format   string
+		template string
+		contains []string
+		wantErr  bool
+	}{
+		{
+			name:     "empty format is raw passthrough",
+			format:   "",
+			contains: []string{secret},
+		},
+		{
+			name:     "raw format",
+			format:   "raw",
+			contains: []string{secret},
+		},
+		{
+			name:     "json format",
+			format:   "json",
+			contains: []string{`"password": "secret123"`},
+		},
+		{
+			name:     "env format",
+			format:   "env",
+			contains: []string{"export PASSWORD='secret123'", "export USERNAME='admin'"},
+		},
+		{
+			name:     "dotenv format",
+			format:   "dotenv",
+			contains: []string{"PASSWORD=secret123", "USERNAME=admin"},
+		},
+		{
+			name:     "yaml format",
+			format:   "yaml",
+			contains: []string{"password: secret123"},
+		},
+		{
+			name:     "template format",
+			format:   "template",
+			template: "user={{.username}}",
+			contains: []string{"user=admin"},
+		},
+		{
+			name:    "template format without --template",
+			format:  "template",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported format",
+			format:  "xml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderOutput(secret, tt.format, tt.template)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("RenderOutput() expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderOutput() unexpected error: %v", err)
+			}
+			for _, substr := range tt.contains {
+				if !strings.Contains(got, substr) {
+					t.Errorf("RenderOutput() = %q, want substring %q", got, substr)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderOutput_DotenvEscapesMultilineValues(t *testing.T) {
+	secret := `{"cert":"-----BEGIN CERTIFICATE-----\nMIIB...\n-----END CERTIFICATE-----"}`
+
+	got, err := RenderOutput(secret, "dotenv", "")
+	if err != nil {
+		t.Fatalf("RenderOutput() unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("RenderOutput() produced %d lines, want 1 (embedded newlines must be escaped): %q", len(lines), got)
+	}
+	want := `CERT="-----BEGIN CERTIFICATE-----\nMIIB...\n-----END CERTIFICATE-----"` + "\n"
+	if got != want {
+		t.Fatalf("RenderOutput() = %q, want %q", got, want)
+	}
+}